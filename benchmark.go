@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// runNeighborIndexBenchmark builds both a Quadtree and a SpatialHash from the
+// same snapshot of boid positions, times how long each takes to insert every
+// boid and then answer one neighborhood query per boid, and prints the
+// results so the two backends can be compared without swapping code.
+func runNeighborIndexBenchmark(boundary Rectangle, neighborhoodRange float64) {
+	positions := positionComponents[readBuffer()]
+
+	benchmark := func(name string, build func() NeighborIndex) {
+		insertStart := time.Now()
+		index := build()
+		insertElapsed := time.Since(insertStart)
+
+		queryStart := time.Now()
+		for i := 0; i < BoidsCount; i++ {
+			rangeRec := NewRectangle(positions[i], neighborhoodRange, neighborhoodRange)
+			index.Query(rangeRec, nil)
+		}
+		queryElapsed := time.Since(queryStart)
+
+		fmt.Printf("[benchmark] %-12s insert=%-12s query=%-12s total=%s\n",
+			name, insertElapsed, queryElapsed, insertElapsed+queryElapsed)
+	}
+
+	benchmark(BackendQuadtree.String(), func() NeighborIndex {
+		return buildQuadtree(boundary, false)
+	})
+
+	benchmark(BackendSpatialHash.String(), func() NeighborIndex {
+		return buildSpatialHash(neighborhoodRange)
+	})
+}
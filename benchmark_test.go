@@ -0,0 +1,59 @@
+package main
+
+import "testing"
+
+// benchBoundary mirrors simulationBoundary and benchRange the default
+// neighborhoodRange newSimulationSystems uses, so `go test -bench` numbers are comparable to the in-app hotkey benchmark.
+const benchRange = 75.0
+
+func benchBoundary() Rectangle {
+	return simulationBoundary()
+}
+
+func BenchmarkQuadtreeInsert(b *testing.B) {
+	seedBoids()
+	boundary := benchBoundary()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		root := buildQuadtree(boundary, false)
+		root.Clear()
+	}
+}
+
+func BenchmarkQuadtreeQuery(b *testing.B) {
+	seedBoids()
+	boundary := benchBoundary()
+	root := buildQuadtree(boundary, false)
+	defer root.Clear()
+	positions := positionComponents[readBuffer()]
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rangeRec := NewRectangle(positions[i%BoidsCount], benchRange, benchRange)
+		root.Query(rangeRec, nil)
+	}
+}
+
+func BenchmarkSpatialHashInsert(b *testing.B) {
+	seedBoids()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		hash := buildSpatialHash(benchRange)
+		hash.Clear()
+	}
+}
+
+func BenchmarkSpatialHashQuery(b *testing.B) {
+	seedBoids()
+	hash := buildSpatialHash(benchRange)
+	defer hash.Clear()
+	positions := positionComponents[readBuffer()]
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rangeRec := NewRectangle(positions[i%BoidsCount], benchRange, benchRange)
+		hash.Query(rangeRec, nil)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"boids/vector"
+	"math"
+)
+
+// Entity is not needed. Boids will be identified by their number from 0 to BoidsCount
+
+// Components
+type Vector2D = vector.Vector2D
+
+type Position = Vector2D
+type Velocity = Vector2D
+type Acceleration = Vector2D
+
+type Rectangle struct {
+	Center     Position
+	Width      float64
+	Height     float64
+	HalfWidth  float64
+	HalfHeight float64
+}
+
+func NewRectangle(center Position, width, height float64) *Rectangle {
+	return &Rectangle{
+		Center:     center,
+		Width:      width,
+		Height:     height,
+		HalfWidth:  width / 2,
+		HalfHeight: height / 2,
+	}
+}
+
+func (r *Rectangle) Contains(point *Position) bool {
+	dx := point.X - r.Center.X
+	dy := point.Y - r.Center.Y
+	return (dx <= r.HalfWidth && dx >= -r.HalfWidth) &&
+		(dy <= r.HalfHeight && dy >= -r.HalfHeight)
+}
+
+func (r *Rectangle) Intersects(rangeRec *Rectangle) bool {
+	dx := rangeRec.Center.X - r.Center.X
+	dy := rangeRec.Center.Y - r.Center.Y
+	return (dx <= (r.HalfWidth+rangeRec.HalfWidth) && dx >= -(r.HalfWidth+rangeRec.HalfWidth)) &&
+		(dy <= (r.HalfHeight+rangeRec.HalfHeight) && dy >= -(r.HalfHeight+rangeRec.HalfHeight))
+}
+
+// ObstacleShape selects how an ObstacleComponent's extent is interpreted.
+type ObstacleShape int
+
+const (
+	ObstacleCircle ObstacleShape = iota
+	ObstacleRectangle
+)
+
+// ObstacleComponent is a static, impassable region boids steer away from.
+// Radius is the collision extent for ObstacleCircle; HalfWidth/HalfHeight are
+// used instead for ObstacleRectangle.
+type ObstacleComponent struct {
+	Position   Position
+	Shape      ObstacleShape
+	Radius     float64
+	HalfWidth  float64
+	HalfHeight float64
+}
+
+// distance returns the boid's distance to the obstacle's edge, clamped above
+// zero so avoidance forces never divide by (or invert) a non-positive value.
+func (o *ObstacleComponent) distance(point *Position) float64 {
+	var d float64
+
+	switch o.Shape {
+	case ObstacleRectangle:
+		dx := math.Max(math.Abs(point.X-o.Position.X)-o.HalfWidth, 0)
+		dy := math.Max(math.Abs(point.Y-o.Position.Y)-o.HalfHeight, 0)
+		d = math.Hypot(dx, dy)
+	default:
+		d = point.Distance(&o.Position) - o.Radius
+	}
+
+	if d < 1 {
+		return 1
+	}
+	return d
+}
+
+// Species tags a boid as predator or prey so the SteeringSystem can apply
+// chase/flee forces between boids of different species.
+type Species int
+
+const (
+	SpeciesPrey Species = iota
+	SpeciesPredator
+)
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+const (
+	boidTriangleBase   = 5.0
+	boidTriangleHeight = 10.0
+)
+
+// EbitenRenderer draws all boids in a single DrawTriangles call: one []Vertex
+// of size 3*BoidsCount and one []uint16 index buffer are rebuilt each frame,
+// with the three rotated vertex positions for each boid written directly
+// (position + heading-derived rotation, no per-sprite matrix multiply). This
+// replaces PixelRenderer's per-boid sprite batch, which caps throughput well
+// below what the GPU can do at 5000+ boids.
+type EbitenRenderer struct {
+	boundary       Rectangle
+	steeringSystem *SteeringSystem
+	movementSystem *MovementSystem
+
+	whitePixel *ebiten.Image
+	canvas     *ebiten.Image
+	vertices   []ebiten.Vertex
+	indices    []uint16
+
+	boidShaderIndex int
+	postEffect      PostEffect
+
+	startTime time.Time
+	lastTick  time.Time
+
+	accumulator *FixedStepAccumulator
+	alpha       float64
+}
+
+func (r *EbitenRenderer) Run(boundary Rectangle, steeringSystem *SteeringSystem, movementSystem *MovementSystem) error {
+	r.boundary = boundary
+	r.steeringSystem = steeringSystem
+	r.movementSystem = movementSystem
+
+	r.whitePixel = ebiten.NewImage(1, 1)
+	r.whitePixel.Fill(color.White)
+	r.canvas = ebiten.NewImage(WindowWidth, WindowHeight)
+
+	r.vertices = make([]ebiten.Vertex, 3*BoidsCount)
+	r.indices = make([]uint16, 3*BoidsCount)
+	for i := range r.indices {
+		r.indices[i] = uint16(i)
+	}
+
+	ebiten.SetWindowSize(WindowWidth, WindowHeight)
+	ebiten.SetWindowTitle("Boids Simulation")
+
+	r.startTime = time.Now()
+	r.lastTick = time.Now()
+	r.accumulator = NewFixedStepAccumulator()
+	return ebiten.RunGame(r)
+}
+
+func (r *EbitenRenderer) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return WindowWidth, WindowHeight
+}
+
+func (r *EbitenRenderer) Update() error {
+	preyTuning := &r.steeringSystem.tuning[SpeciesPrey]
+	adjustFactor := 0.1
+	if inpututil.IsKeyJustPressed(ebiten.KeyW) {
+		preyTuning.cohesionFactor += adjustFactor
+	} else if inpututil.IsKeyJustPressed(ebiten.KeyS) {
+		preyTuning.cohesionFactor -= adjustFactor
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyA) {
+		preyTuning.alignmentFactor += adjustFactor
+	} else if inpututil.IsKeyJustPressed(ebiten.KeyD) {
+		preyTuning.alignmentFactor -= adjustFactor
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyQ) {
+		preyTuning.separationFactor += adjustFactor
+	} else if inpututil.IsKeyJustPressed(ebiten.KeyE) {
+		preyTuning.separationFactor -= adjustFactor
+	}
+
+	adjustRange := 1.0
+	if inpututil.IsKeyJustPressed(ebiten.KeyZ) {
+		r.steeringSystem.neighborhoodRange += adjustRange
+	} else if inpututil.IsKeyJustPressed(ebiten.KeyX) {
+		r.steeringSystem.neighborhoodRange -= adjustRange
+	}
+
+	adjustSpeed := 0.1
+	if inpututil.IsKeyJustPressed(ebiten.KeyR) {
+		r.steeringSystem.maxSpeed += adjustSpeed
+	} else if inpututil.IsKeyJustPressed(ebiten.KeyF) {
+		r.steeringSystem.maxSpeed -= adjustSpeed
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyP) {
+		r.steeringSystem.sequential = !r.steeringSystem.sequential
+		fmt.Printf("Sequential steering: %t\n", r.steeringSystem.sequential)
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyT) {
+		r.boidShaderIndex = (r.boidShaderIndex + 1) % len(boidShaders)
+		fmt.Printf("Boid shader: %s\n", boidShaders[r.boidShaderIndex].name)
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyY) {
+		r.postEffect = (r.postEffect + 1) % postEffectCount
+		fmt.Printf("Post effect: %s\n", r.postEffect)
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyN) {
+		indexBackend = (indexBackend + 1) % indexBackendCount
+		fmt.Printf("Neighbor index backend: %s\n", indexBackend)
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyB) {
+		runNeighborIndexBenchmark(r.boundary, r.steeringSystem.neighborhoodRange)
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyO) || inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		x, y := ebiten.CursorPosition()
+		obstacleComponents = append(obstacleComponents, ObstacleComponent{
+			Position: Vector2D{X: float64(x), Y: WindowHeight - float64(y)},
+			Shape:    ObstacleCircle,
+			Radius:   obstacleRadius,
+		})
+		fmt.Printf("Spawned obstacle at (%d, %d)\n", x, y)
+	}
+
+	start := time.Now()
+	r.alpha = r.accumulator.Advance(r.boundary, r.steeringSystem, r.movementSystem)
+	elapsed := time.Since(start)
+	fmt.Printf("Iteration took %d ms\n", elapsed.Milliseconds())
+
+	return nil
+}
+
+func (r *EbitenRenderer) Draw(screen *ebiten.Image) {
+	const halfBase = boidTriangleBase / 2
+	const halfHeight = boidTriangleHeight / 2
+
+	shade := boidShaders[r.boidShaderIndex].shade
+	now := time.Since(r.startTime).Seconds()
+
+	for i := 0; i < BoidsCount; i++ {
+		position := interpolatedPosition(i, r.alpha)
+		velocity := interpolatedVelocity(i, r.alpha)
+
+		angle := math.Atan2(velocity.Y, velocity.X) + math.Pi/2
+		sin, cos := math.Sincos(angle)
+
+		// Local triangle: apex ahead along local +Y, base behind it.
+		apex := rotatePoint(0, halfHeight, sin, cos)
+		left := rotatePoint(-halfBase, -halfHeight, sin, cos)
+		right := rotatePoint(halfBase, -halfHeight, sin, cos)
+
+		// Ebiten's Y axis grows downward; the simulation's grows upward, so
+		// flip Y on the way to screen space.
+		px, py := position.X, WindowHeight-position.Y
+
+		cr, cg, cb := shade(BoidShaderInput{
+			Position:      position,
+			Velocity:      velocity,
+			NeighborCount: neighborCountComponents[i],
+			Time:          now,
+		})
+
+		base := 3 * i
+		r.vertices[base] = boidVertex(px+apex.X, py-apex.Y, cr, cg, cb)
+		r.vertices[base+1] = boidVertex(px+left.X, py-left.Y, cr, cg, cb)
+		r.vertices[base+2] = boidVertex(px+right.X, py-right.Y, cr, cg, cb)
+	}
+
+	r.canvas.Clear()
+	opts := &ebiten.DrawTrianglesOptions{}
+	r.canvas.DrawTriangles(r.vertices, r.indices, r.whitePixel, opts)
+
+	applyPostEffect(screen, r.canvas, r.postEffect)
+}
+
+func rotatePoint(x, y, sin, cos float64) Vector2D {
+	return Vector2D{
+		X: x*cos - y*sin,
+		Y: x*sin + y*cos,
+	}
+}
+
+func boidVertex(x, y float64, r, g, b float32) ebiten.Vertex {
+	return ebiten.Vertex{
+		DstX:   float32(x),
+		DstY:   float32(y),
+		SrcX:   0,
+		SrcY:   0,
+		ColorR: r,
+		ColorG: g,
+		ColorB: b,
+		ColorA: 1,
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// runHeadless seeds the boid population and runs tickCount fixed-timestep
+// simulation ticks with no window, writing a CSV of average neighbor counts
+// and per-tick wall time to out. This decouples benchmarking and steering
+// parameter regression checks from render frame rate.
+func runHeadless(tickCount int, out io.Writer) error {
+	seedBoids()
+	steeringSystem, movementSystem := newSimulationSystems()
+	boundary := simulationBoundary()
+
+	if _, err := fmt.Fprintln(out, "tick,avg_neighbor_count,tick_ms"); err != nil {
+		return err
+	}
+
+	for tick := 0; tick < tickCount; tick++ {
+		start := time.Now()
+		stepSimulation(boundary, steeringSystem, movementSystem, simDt)
+		elapsed := time.Since(start)
+
+		total := 0
+		for i := 0; i < BoidsCount; i++ {
+			total += neighborCountComponents[i]
+		}
+		avgNeighborCount := float64(total) / float64(BoidsCount)
+
+		if _, err := fmt.Fprintf(out, "%d,%f,%f\n", tick, avgNeighborCount, elapsed.Seconds()*1000); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package main
+
+// NeighborIndex is the common interface both spatial backends implement, so
+// SteeringSystem can query nearby boids/obstacles without caring which
+// structure built the index for this tick.
+type NeighborIndex interface {
+	Insert(position *Position, entity QuadtreeEntity) bool
+	Query(rangeRec *Rectangle, foundEntities []QuadtreeEntity) []QuadtreeEntity
+	Clear()
+}
+
+// IndexBackend selects which NeighborIndex implementation buildNeighborIndex
+// produces.
+type IndexBackend int
+
+const (
+	BackendQuadtree IndexBackend = iota
+	BackendSpatialHash
+	indexBackendCount
+)
+
+var indexBackendNames = [indexBackendCount]string{"quadtree", "spatial-hash"}
+
+func (b IndexBackend) String() string {
+	return indexBackendNames[b]
+}
+
+// indexBackend is the backend stepSimulation rebuilds the neighbor index
+// with each tick; toggle it at runtime with a hotkey to compare backends
+// live, or use runNeighborIndexBenchmark for a one-off timing comparison.
+var indexBackend = BackendQuadtree
+
+// buildNeighborIndex inserts every boid and obstacle into a fresh index of
+// the selected backend, rooted at boundary. parallel only affects the
+// quadtree backend; the spatial hash's insert is already O(1) per boid so
+// there's nothing to gain from sharding it. cellSize sizes the spatial hash's
+// buckets and should match the caller's query range.
+func buildNeighborIndex(boundary Rectangle, backend IndexBackend, parallel bool, cellSize float64) NeighborIndex {
+	if backend == BackendSpatialHash {
+		return buildSpatialHash(cellSize)
+	}
+	return buildQuadtree(boundary, parallel)
+}
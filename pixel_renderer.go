@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"time"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+	"golang.org/x/image/colornames"
+)
+
+// PixelRenderer draws boids with faiface/pixel, batching one sprite draw call
+// per boid. faiface/pixel is unmaintained and this path is being replaced by
+// EbitenRenderer's DrawTriangles pipeline; it's kept around so the migration
+// can happen incrementally.
+type PixelRenderer struct{}
+
+func (PixelRenderer) Run(boundary Rectangle, steeringSystem *SteeringSystem, movementSystem *MovementSystem) error {
+	cfg := pixelgl.WindowConfig{
+		Title:  "Boids Simulation",
+		Bounds: pixel.R(0, 0, WindowWidth, WindowHeight),
+	}
+
+	win, err := pixelgl.NewWindow(cfg)
+	if err != nil {
+		return err
+	}
+
+	boidSprite := createIsoscelesTriangleSprite(5, 10) // Adjust baseLength and height as needed
+	batch := pixel.NewBatch(&pixel.TrianglesData{}, boidSprite.Picture())
+
+	accumulator := NewFixedStepAccumulator()
+	alpha := 0.0
+
+	for !win.Closed() {
+		start := time.Now()
+
+		// Runtime parameter adjustments (prey tuning; predators are tuned separately)
+		preyTuning := &steeringSystem.tuning[SpeciesPrey]
+		adjustFactor := 0.1
+		if win.JustPressed(pixelgl.KeyW) {
+			preyTuning.cohesionFactor += adjustFactor
+			fmt.Printf("Cohesion Factor: %f\n", preyTuning.cohesionFactor)
+		} else if win.JustPressed(pixelgl.KeyS) {
+			preyTuning.cohesionFactor -= adjustFactor
+			fmt.Printf("Cohesion Factor: %f\n", preyTuning.cohesionFactor)
+		}
+
+		if win.JustPressed(pixelgl.KeyA) {
+			preyTuning.alignmentFactor += adjustFactor
+			fmt.Printf("Alignment Factor: %f\n", preyTuning.alignmentFactor)
+		} else if win.JustPressed(pixelgl.KeyD) {
+			preyTuning.alignmentFactor -= adjustFactor
+			fmt.Printf("Alignment Factor: %f\n", preyTuning.alignmentFactor)
+		}
+
+		if win.JustPressed(pixelgl.KeyQ) {
+			preyTuning.separationFactor += adjustFactor
+			fmt.Printf("Separation Factor: %f\n", preyTuning.separationFactor)
+		} else if win.JustPressed(pixelgl.KeyE) {
+			preyTuning.separationFactor -= adjustFactor
+			fmt.Printf("Separation Factor: %f\n", preyTuning.separationFactor)
+		}
+
+		adjustRange := 1.0 // Adjust range by a bit larger amount
+		if win.JustPressed(pixelgl.KeyZ) {
+			steeringSystem.neighborhoodRange += adjustRange
+			fmt.Printf("Neighborhood Range: %f\n", steeringSystem.neighborhoodRange)
+		} else if win.JustPressed(pixelgl.KeyX) {
+			steeringSystem.neighborhoodRange -= adjustRange
+			fmt.Printf("Neighborhood Range: %f\n", steeringSystem.neighborhoodRange)
+		}
+
+		adjustSpeed := 0.1
+		if win.JustPressed(pixelgl.KeyR) {
+			steeringSystem.maxSpeed += adjustSpeed
+			fmt.Printf("Max Speed: %f\n", steeringSystem.maxSpeed)
+		} else if win.JustPressed(pixelgl.KeyF) {
+			steeringSystem.maxSpeed -= adjustSpeed
+			fmt.Printf("Max Speed: %f\n", steeringSystem.maxSpeed)
+		}
+
+		if win.JustPressed(pixelgl.KeyP) {
+			steeringSystem.sequential = !steeringSystem.sequential
+			fmt.Printf("Sequential steering: %t\n", steeringSystem.sequential)
+		}
+
+		if win.JustPressed(pixelgl.KeyN) {
+			indexBackend = (indexBackend + 1) % indexBackendCount
+			fmt.Printf("Neighbor index backend: %s\n", indexBackend)
+		}
+
+		if win.JustPressed(pixelgl.KeyB) {
+			runNeighborIndexBenchmark(boundary, steeringSystem.neighborhoodRange)
+		}
+
+		// Spawn an obstacle at the mouse cursor, either via the O key or a click.
+		if win.JustPressed(pixelgl.KeyO) || win.JustPressed(pixelgl.MouseButtonLeft) {
+			click := win.MousePosition()
+			obstacleComponents = append(obstacleComponents, ObstacleComponent{
+				Position: Vector2D{X: click.X, Y: click.Y},
+				Shape:    ObstacleCircle,
+				Radius:   obstacleRadius,
+			})
+			fmt.Printf("Spawned obstacle at (%f, %f)\n", click.X, click.Y)
+		}
+
+		win.Clear(colornames.Black)
+
+		updatePixelSprites(boidSprite, batch, win, alpha)
+		batch.Clear()
+
+		alpha = accumulator.Advance(boundary, steeringSystem, movementSystem)
+
+		win.Update()
+
+		elapsed := time.Since(start)
+		fmt.Printf("Iteration took %d ms\n", elapsed.Milliseconds())
+	}
+
+	return nil
+}
+
+func updatePixelSprites(boidSprite *pixel.Sprite, batch *pixel.Batch, win *pixelgl.Window, alpha float64) {
+	for i := 0; i < BoidsCount; i++ {
+		position := interpolatedPosition(i, alpha)
+		velocity := interpolatedVelocity(i, alpha)
+
+		pos := pixel.V(position.X, position.Y)
+
+		angle := math.Atan2(velocity.Y, velocity.X) + math.Pi/2
+		mat := pixel.IM.Moved(pos).Rotated(pos, angle)
+
+		boidSprite.Draw(batch, mat)
+	}
+	batch.Draw(win)
+}
+
+func createIsoscelesTriangleSprite(baseLength, height float64) *pixel.Sprite {
+	img := image.NewRGBA(image.Rect(0, 0, int(baseLength), int(height)))
+	col := color.RGBA{255, 0, 0, 255} // Red color for our boid triangles
+
+	// Points of the triangle
+	top := pixel.V(baseLength/2, height)
+	left := pixel.V(0, 0)
+	right := pixel.V(baseLength, 0)
+
+	// Draw the triangle onto the image
+	for x := 0.0; x <= baseLength; x++ {
+		for y := 0.0; y <= height; y++ {
+			pos := pixel.V(x, y)
+			if insideTriangle(pos, top, left, right) {
+				img.Set(int(x), int(y), col)
+			}
+		}
+	}
+
+	pic := pixel.PictureDataFromImage(img)
+	return pixel.NewSprite(pic, pic.Bounds())
+}
+
+func insideTriangle(pt, v1, v2, v3 pixel.Vec) bool {
+	d1 := sign(pt, v1, v2)
+	d2 := sign(pt, v2, v3)
+	d3 := sign(pt, v3, v1)
+
+	hasNeg := (d1 < 0) || (d2 < 0) || (d3 < 0)
+	hasPos := (d1 > 0) || (d2 > 0) || (d3 > 0)
+
+	return !(hasNeg && hasPos)
+}
+
+func sign(p1, p2, p3 pixel.Vec) float64 {
+	return (p1.X-p3.X)*(p2.Y-p3.Y) - (p2.X-p3.X)*(p1.Y-p3.Y)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// PostEffect selects a full-screen Kage shader pass applied after the boid
+// triangles are drawn to an offscreen canvas.
+type PostEffect int
+
+const (
+	PostEffectNone PostEffect = iota
+	PostEffectBloom
+	PostEffectChromaticAberration
+	PostEffectVignette
+	postEffectCount
+)
+
+var postEffectNames = [postEffectCount]string{"none", "bloom", "chromatic-aberration", "vignette"}
+
+func (e PostEffect) String() string {
+	return postEffectNames[e]
+}
+
+const bloomShaderSrc = `
+package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	c := imageSrc0At(texCoord)
+	spread := 0.0035
+	bloom := imageSrc0At(texCoord + vec2(spread, spread)) +
+		imageSrc0At(texCoord - vec2(spread, spread)) +
+		imageSrc0At(texCoord + vec2(spread, -spread)) +
+		imageSrc0At(texCoord - vec2(spread, -spread))
+	return c + bloom*0.2
+}
+`
+
+const chromaticAberrationShaderSrc = `
+package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	offset := vec2(0.003, 0.0)
+	r := imageSrc0At(texCoord + offset).r
+	g := imageSrc0At(texCoord).g
+	b := imageSrc0At(texCoord - offset).b
+	a := imageSrc0At(texCoord).a
+	return vec4(r, g, b, a)
+}
+`
+
+const vignetteShaderSrc = `
+package main
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	c := imageSrc0At(texCoord)
+	center := vec2(0.5, 0.5)
+	d := length(texCoord - center)
+	vignette := smoothstep(0.8, 0.3, d)
+	return vec4(c.rgb*vignette, c.a)
+}
+`
+
+// postShaders holds the compiled Kage shader for every effect except
+// PostEffectNone, which is just a straight copy.
+var postShaders = map[PostEffect]*ebiten.Shader{}
+
+func init() {
+	sources := map[PostEffect]string{
+		PostEffectBloom:               bloomShaderSrc,
+		PostEffectChromaticAberration: chromaticAberrationShaderSrc,
+		PostEffectVignette:            vignetteShaderSrc,
+	}
+
+	for effect, src := range sources {
+		shader, err := ebiten.NewShader([]byte(src))
+		if err != nil {
+			panic(fmt.Sprintf("compiling %s post shader: %v", effect, err))
+		}
+		postShaders[effect] = shader
+	}
+}
+
+// applyPostEffect draws src onto dst, running it through the compiled Kage
+// shader for effect; PostEffectNone copies src through unmodified.
+func applyPostEffect(dst, src *ebiten.Image, effect PostEffect) {
+	if effect == PostEffectNone {
+		dst.DrawImage(src, nil)
+		return
+	}
+
+	opts := &ebiten.DrawRectShaderOptions{}
+	opts.Images[0] = src
+
+	dst.DrawRectShader(WindowWidth, WindowHeight, postShaders[effect], opts)
+}
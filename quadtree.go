@@ -0,0 +1,211 @@
+package main
+
+import "sync"
+
+const capacity = 8 // adjust as needed
+
+// EntityKind distinguishes the kinds of entities the quadtree can index, so a
+// single tree can answer neighbour queries across boids and obstacles alike.
+type EntityKind int
+
+const (
+	EntityBoid EntityKind = iota
+	EntityObstacle
+)
+
+// QuadtreeEntity is a typed reference into one of the component arrays. Index
+// is resolved against positionComponents or obstacleComponents depending on
+// Kind.
+type QuadtreeEntity struct {
+	Kind  EntityKind
+	Index int
+}
+
+func (e QuadtreeEntity) position() *Position {
+	switch e.Kind {
+	case EntityObstacle:
+		return &obstacleComponents[e.Index].Position
+	default:
+		return &positionComponents[readBuffer()][e.Index]
+	}
+}
+
+var quadtreePool = sync.Pool{
+	New: func() interface{} {
+		return &Quadtree{
+			Components: make([]QuadtreeEntity, 0, capacity),
+		}
+	},
+}
+
+type Quadtree struct {
+	Boundary       Rectangle
+	Components     []QuadtreeEntity
+	Divided        bool
+	NW, NE, SW, SE *Quadtree
+}
+
+func NewQuadtree(boundary Rectangle) *Quadtree {
+	qt := quadtreePool.Get().(*Quadtree)
+	qt.Boundary = boundary
+	return qt
+}
+
+func (qt *Quadtree) Clear() {
+	qt.Components = qt.Components[:0]
+	qt.Divided = false
+	if qt.NW != nil {
+		qt.NW.Clear()
+		quadtreePool.Put(qt.NW)
+		qt.NW = nil
+	}
+
+	if qt.NE != nil {
+		qt.NE.Clear()
+		quadtreePool.Put(qt.NE)
+		qt.NE = nil
+	}
+
+	if qt.SW != nil {
+		qt.SW.Clear()
+		quadtreePool.Put(qt.SW)
+		qt.SW = nil
+	}
+
+	if qt.SE != nil {
+		qt.SE.Clear()
+		quadtreePool.Put(qt.SE)
+		qt.SE = nil
+	}
+}
+
+func (qt *Quadtree) Subdivide() {
+	x, y := qt.Boundary.Center.X, qt.Boundary.Center.Y
+	w, h := qt.Boundary.Width/2, qt.Boundary.Height/2
+	qt.NW = NewQuadtree(*NewRectangle(Vector2D{X: x - w/2, Y: y - h/2}, w, h))
+	qt.NE = NewQuadtree(*NewRectangle(Vector2D{X: x + w/2, Y: y - h/2}, w, h))
+	qt.SW = NewQuadtree(*NewRectangle(Vector2D{X: x - w/2, Y: y + h/2}, w, h))
+	qt.SE = NewQuadtree(*NewRectangle(Vector2D{X: x + w/2, Y: y + h/2}, w, h))
+	qt.Divided = true
+}
+
+func (qt *Quadtree) Insert(position *Position, entity QuadtreeEntity) bool {
+	if !qt.Boundary.Contains(position) {
+		return false
+	}
+
+	if len(qt.Components) < capacity {
+		qt.Components = append(qt.Components, entity)
+		return true
+	}
+
+	if !qt.Divided {
+		qt.Subdivide()
+	}
+
+	return qt.child(position).Insert(position, entity)
+}
+
+// child picks the one quadrant position belongs to by comparing it against
+// the boundary's own center, the same convention partitionByQuadrant uses.
+// The old implementation instead tried qt.NW.Insert() || qt.NE.Insert() ||
+// ... in sequence, relying on each child's Contains (closed intervals on
+// every side) to reject a point outside it; since adjacent quadrants share a
+// boundary edge, a point sitting exactly on it is "contained" by more than
+// one child, and floating point error a few subdivisions deep could make it
+// fail every child's Contains and get silently dropped. Routing by center
+// comparison always picks exactly one child, and that child's Contains is
+// guaranteed to hold because qt's own Contains already passed.
+func (qt *Quadtree) child(position *Position) *Quadtree {
+	center := qt.Boundary.Center
+	switch {
+	case position.X <= center.X && position.Y <= center.Y:
+		return qt.NW
+	case position.X > center.X && position.Y <= center.Y:
+		return qt.NE
+	case position.X <= center.X && position.Y > center.Y:
+		return qt.SW
+	default:
+		return qt.SE
+	}
+}
+
+func (qt *Quadtree) Query(rangeRec *Rectangle, foundEntities []QuadtreeEntity) []QuadtreeEntity {
+	if !qt.Boundary.Intersects(rangeRec) {
+		return foundEntities
+	}
+
+	for _, entity := range qt.Components {
+		if rangeRec.Contains(entity.position()) {
+			foundEntities = append(foundEntities, entity)
+		}
+	}
+
+	if qt.Divided {
+		foundEntities = qt.NW.Query(rangeRec, foundEntities)
+		foundEntities = qt.NE.Query(rangeRec, foundEntities)
+		foundEntities = qt.SW.Query(rangeRec, foundEntities)
+		foundEntities = qt.SE.Query(rangeRec, foundEntities)
+	}
+
+	return foundEntities
+}
+
+// buildQuadtree inserts every boid and obstacle into a fresh tree rooted at
+// boundary. When parallel is true the boids are first bucketed into the four
+// root quadrants so each quadrant can be populated by its own goroutine; the
+// root is forced to subdivide up front to make that split possible. Obstacles
+// are few enough that they're always inserted sequentially after.
+func buildQuadtree(boundary Rectangle, parallel bool) *Quadtree {
+	root := NewQuadtree(boundary)
+
+	if !parallel {
+		for i := 0; i < BoidsCount; i++ {
+			root.Insert(&positionComponents[readBuffer()][i], QuadtreeEntity{Kind: EntityBoid, Index: i})
+		}
+	} else {
+		root.Subdivide()
+
+		buckets := partitionByQuadrant(&positionComponents[readBuffer()], root.Boundary.Center)
+		children := [4]*Quadtree{root.NW, root.NE, root.SW, root.SE}
+
+		var wg sync.WaitGroup
+		for q := 0; q < 4; q++ {
+			wg.Add(1)
+			go func(child *Quadtree, indices []int) {
+				defer wg.Done()
+				for _, i := range indices {
+					child.Insert(&positionComponents[readBuffer()][i], QuadtreeEntity{Kind: EntityBoid, Index: i})
+				}
+			}(children[q], buckets[q])
+		}
+		wg.Wait()
+	}
+
+	for i := range obstacleComponents {
+		root.Insert(&obstacleComponents[i].Position, QuadtreeEntity{Kind: EntityObstacle, Index: i})
+	}
+
+	return root
+}
+
+// partitionByQuadrant buckets boid indices by which of the root's four
+// quadrants their current position falls in, so each bucket can be inserted
+// into its quadrant's subtree independently.
+func partitionByQuadrant(positions *[BoidsCount]Position, center Position) [4][]int {
+	var buckets [4][]int
+	for i := 0; i < BoidsCount; i++ {
+		p := &positions[i]
+		switch {
+		case p.X <= center.X && p.Y <= center.Y:
+			buckets[0] = append(buckets[0], i) // NW
+		case p.X > center.X && p.Y <= center.Y:
+			buckets[1] = append(buckets[1], i) // NE
+		case p.X <= center.X && p.Y > center.Y:
+			buckets[2] = append(buckets[2], i) // SW
+		default:
+			buckets[3] = append(buckets[3], i) // SE
+		}
+	}
+	return buckets
+}
@@ -0,0 +1,39 @@
+package main
+
+import "testing"
+
+// TestQuadtreeInsertOnSubdivisionBoundary guards the bug fixed in child():
+// once a node subdivides, a point sitting exactly on the shared edge between
+// children passes more than one child's Contains check (closed intervals on
+// all four sides); it must be routed to exactly one child and stay queryable.
+func TestQuadtreeInsertOnSubdivisionBoundary(t *testing.T) {
+	boundary := *NewRectangle(Vector2D{X: 0, Y: 0}, 100, 100)
+	root := NewQuadtree(boundary)
+	defer root.Clear()
+
+	// Fill past capacity with points off-center so the node subdivides.
+	for i := 0; i < capacity; i++ {
+		positionComponents[0][i] = Vector2D{X: -40, Y: -40}
+		if !root.Insert(&positionComponents[0][i], QuadtreeEntity{Kind: EntityBoid, Index: i}) {
+			t.Fatalf("seed insert %d failed", i)
+		}
+	}
+
+	// This point sits exactly on the root's center, i.e. on the boundary
+	// shared by all four children once the node subdivides.
+	boundaryIndex := capacity
+	positionComponents[0][boundaryIndex] = Vector2D{X: 0, Y: 0}
+	entity := QuadtreeEntity{Kind: EntityBoid, Index: boundaryIndex}
+	if !root.Insert(&positionComponents[0][boundaryIndex], entity) {
+		t.Fatalf("Insert of boundary point returned false, point was dropped")
+	}
+
+	queryRange := NewRectangle(Vector2D{X: 0, Y: 0}, 1, 1)
+	found := root.Query(queryRange, nil)
+	for _, e := range found {
+		if e == entity {
+			return
+		}
+	}
+	t.Fatalf("boundary point not retrievable via Query, got %v", found)
+}
@@ -0,0 +1,11 @@
+package main
+
+// Renderer owns the application window, input handling, and per-frame draw
+// call. Run blocks until the window is closed, advancing the simulation by
+// zero or more fixed-timestep ticks and drawing the boid state once per
+// frame. Multiple Renderer
+// implementations can coexist so a rendering backend migration can be done
+// incrementally.
+type Renderer interface {
+	Run(boundary Rectangle, steeringSystem *SteeringSystem, movementSystem *MovementSystem) error
+}
@@ -0,0 +1,85 @@
+package main
+
+import "math"
+
+// BoidShaderInput is everything a boid color shader needs to derive a color
+// for one boid on one frame.
+type BoidShaderInput struct {
+	Position      Position
+	Velocity      Velocity
+	NeighborCount int
+	Time          float64
+}
+
+// BoidShader computes a boid's color (each channel in [0,1]) from its
+// current state. It's evaluated on the CPU once per boid per frame and
+// written straight into that boid's vertex colors.
+type BoidShader func(in BoidShaderInput) (r, g, b float32)
+
+type namedBoidShader struct {
+	name  string
+	shade BoidShader
+}
+
+// boidShaders are the built-in shaders, cycled through with a hotkey.
+var boidShaders = []namedBoidShader{
+	{"heading-hue", headingHueShader},
+	{"density-heatmap", densityHeatmapShader},
+	{"speed-gradient", speedGradientShader},
+}
+
+// headingHueShader colors a boid by its heading angle, mapped around the hue
+// wheel.
+func headingHueShader(in BoidShaderInput) (float32, float32, float32) {
+	angle := math.Atan2(in.Velocity.Y, in.Velocity.X)
+	hue := (angle + math.Pi) / (2 * math.Pi)
+	return hsvToRGB(hue, 1, 1)
+}
+
+// densityHeatmapShader colors a boid from blue (few neighbors) to red (many).
+func densityHeatmapShader(in BoidShaderInput) (float32, float32, float32) {
+	const maxExpectedNeighbors = 30
+	t := float64(in.NeighborCount) / maxExpectedNeighbors
+	if t > 1 {
+		t = 1
+	}
+	return hsvToRGB(0.66*(1-t), 1, 1)
+}
+
+// speedGradientShader colors a boid from dim (slow) to bright orange (fast).
+func speedGradientShader(in BoidShaderInput) (float32, float32, float32) {
+	const maxExpectedSpeed = 4.0
+	speed := math.Hypot(in.Velocity.X, in.Velocity.Y)
+	t := speed / maxExpectedSpeed
+	if t > 1 {
+		t = 1
+	}
+	v := float32(0.3 + 0.7*t)
+	return v, v * 0.4, v * 0.1
+}
+
+// hsvToRGB converts HSV (each in [0,1]) to RGB (each in [0,1]).
+func hsvToRGB(h, s, v float64) (float32, float32, float32) {
+	i := math.Floor(h * 6)
+	f := h*6 - i
+	p := v * (1 - s)
+	q := v * (1 - f*s)
+	t := v * (1 - (1-f)*s)
+
+	var r, g, b float64
+	switch int(i) % 6 {
+	case 0:
+		r, g, b = v, t, p
+	case 1:
+		r, g, b = q, v, p
+	case 2:
+		r, g, b = p, v, t
+	case 3:
+		r, g, b = p, q, v
+	case 4:
+		r, g, b = t, p, v
+	default:
+		r, g, b = v, p, q
+	}
+	return float32(r), float32(g), float32(b)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+// simTickRate is the fixed rate SteeringSystem/MovementSystem are stepped at,
+// independent of the render frame rate.
+const simTickRate = 60.0
+const simDt = 1.0 / simTickRate
+
+// stepSimulation advances the boid simulation by one fixed-size tick of
+// length dt: it rebuilds the neighbor index from the current (read) buffer,
+// runs steering and movement, then swaps the double buffer so the next tick
+// reads this tick's result.
+func stepSimulation(boundary Rectangle, steeringSystem *SteeringSystem, movementSystem *MovementSystem, dt float64) {
+	neighborIndex = buildNeighborIndex(boundary, indexBackend, !steeringSystem.sequential, steeringSystem.neighborhoodRange)
+
+	steeringSystem.Update()
+	movementSystem.Update(dt)
+
+	neighborIndex.Clear()
+
+	currentBuffer = writeBuffer()
+}
+
+// FixedStepAccumulator decouples simulation from render rate: it runs zero or
+// more simDt-sized ticks per call to Advance, catching up to however much
+// real time has elapsed, and reports how far between the last two committed
+// states the render should interpolate.
+type FixedStepAccumulator struct {
+	accumulated float64
+	lastFrame   time.Time
+
+	// maxStepsPerFrame bounds catch-up work after a stall (e.g. the window
+	// was dragged or the process was paused) so the sim can't spiral into
+	// running forever trying to consume a huge backlog of real time.
+	maxStepsPerFrame int
+}
+
+func NewFixedStepAccumulator() *FixedStepAccumulator {
+	return &FixedStepAccumulator{
+		lastFrame:        time.Now(),
+		maxStepsPerFrame: 5,
+	}
+}
+
+// Advance steps the simulation by as many simDt ticks as real time elapsed
+// since the last call warrants, and returns the interpolation alpha in
+// [0, 1] between the previous and current committed state for rendering.
+// maxStepsPerFrame can leave a.accumulated >= simDt after a stall, so the
+// result is clamped to 1 rather than letting the caller extrapolate past the
+// current committed state.
+func (a *FixedStepAccumulator) Advance(boundary Rectangle, steeringSystem *SteeringSystem, movementSystem *MovementSystem) float64 {
+	now := time.Now()
+	frameTime := now.Sub(a.lastFrame).Seconds()
+	a.lastFrame = now
+
+	const maxFrameTime = 0.25 // clamp so a stall doesn't demand a huge catch-up
+	if frameTime > maxFrameTime {
+		frameTime = maxFrameTime
+	}
+
+	a.accumulated += frameTime
+
+	steps := 0
+	for a.accumulated >= simDt && steps < a.maxStepsPerFrame {
+		stepSimulation(boundary, steeringSystem, movementSystem, simDt)
+		a.accumulated -= simDt
+		steps++
+	}
+
+	return math.Min(a.accumulated/simDt, 1.0)
+}
+
+// interpolatedPosition blends boid i's previous and current committed
+// position by alpha (as returned by FixedStepAccumulator.Advance), so
+// rendering between two sim ticks doesn't look stepped.
+func interpolatedPosition(i int, alpha float64) Position {
+	prev := positionComponents[writeBuffer()][i]
+	curr := positionComponents[readBuffer()][i]
+	return Position{
+		X: prev.X + (curr.X-prev.X)*alpha,
+		Y: prev.Y + (curr.Y-prev.Y)*alpha,
+	}
+}
+
+// interpolatedVelocity blends boid i's previous and current committed
+// velocity by alpha, primarily so rendering can derive a smooth heading.
+func interpolatedVelocity(i int, alpha float64) Velocity {
+	prev := velocityComponents[writeBuffer()][i]
+	curr := velocityComponents[readBuffer()][i]
+	return Velocity{
+		X: prev.X + (curr.X-prev.X)*alpha,
+		Y: prev.Y + (curr.Y-prev.Y)*alpha,
+	}
+}
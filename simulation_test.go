@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestAccumulator(elapsed time.Duration) *FixedStepAccumulator {
+	a := NewFixedStepAccumulator()
+	a.lastFrame = time.Now().Add(-elapsed)
+	return a
+}
+
+// TestFixedStepAccumulatorAdvance checks that Advance runs one tick per simDt
+// of elapsed time and reports the leftover fraction as the interpolation
+// alpha.
+func TestFixedStepAccumulatorAdvance(t *testing.T) {
+	seedBoids()
+	steeringSystem, movementSystem := newSimulationSystems()
+	boundary := simulationBoundary()
+
+	a := newTestAccumulator(time.Duration(2.5 * simDt * float64(time.Second)))
+	alpha := a.Advance(boundary, steeringSystem, movementSystem)
+
+	if alpha < 0 || alpha >= 1 {
+		t.Fatalf("alpha out of range [0, 1): got %f", alpha)
+	}
+	if alpha < 0.3 || alpha > 0.7 {
+		t.Fatalf("expected alpha near 0.5 after 2.5 ticks of elapsed time, got %f", alpha)
+	}
+}
+
+// TestFixedStepAccumulatorCatchUpClamp checks that a long stall is bounded by
+// maxStepsPerFrame rather than spending real time draining the whole backlog
+// in one call.
+func TestFixedStepAccumulatorCatchUpClamp(t *testing.T) {
+	seedBoids()
+	steeringSystem, movementSystem := newSimulationSystems()
+	boundary := simulationBoundary()
+
+	a := newTestAccumulator(10 * time.Second)
+	alpha := a.Advance(boundary, steeringSystem, movementSystem)
+
+	if a.accumulated <= 0 {
+		t.Fatalf("expected leftover backlog after a capped catch-up, got accumulated=%f", a.accumulated)
+	}
+	if alpha < 0 || alpha > 1 {
+		t.Fatalf("alpha out of range [0, 1] even with leftover backlog: got %f", alpha)
+	}
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"math"
+	"sync"
+)
+
+type MovementSystem struct {
+	maxSpeed float64
+}
+
+// Update integrates position and velocity by dt: Position += Velocity*dt and
+// Velocity += Acceleration*dt, so simulation speed doesn't depend on how
+// often Update is called.
+func (ms *MovementSystem) Update(dt float64) {
+	read := readBuffer()
+	write := writeBuffer()
+
+	readPos := &positionComponents[read]
+	writePos := &positionComponents[write]
+	readVel := &velocityComponents[read]
+	writeVel := &velocityComponents[write]
+
+	for i := 0; i < BoidsCount; i++ {
+		pos := readPos[i]
+		vel := readVel[i]
+
+		posDelta := vel
+		posDelta.Multiply(dt)
+		pos.Add(&posDelta)
+
+		velDelta := accelerationComponents[i]
+		velDelta.Multiply(dt)
+		vel.Add(&velDelta)
+
+		vel.Limit(ms.maxSpeed)
+		accelerationComponents[i].Multiply(0)
+
+		// If boid crosses left boundary
+		if pos.X < 0 {
+			pos.X = WindowWidth
+		}
+
+		// If boid crosses right boundary
+		if pos.X > WindowWidth {
+			pos.X = 0
+		}
+
+		// If boid crosses bottom boundary
+		if pos.Y < 0 {
+			pos.Y = WindowHeight
+		}
+
+		// If boid crosses top boundary
+		if pos.Y > WindowHeight {
+			pos.Y = 0
+		}
+
+		writePos[i] = pos
+		writeVel[i] = vel
+	}
+}
+
+// SpeciesTuning holds the per-species steering weights so predators and prey
+// can flock, avoid obstacles, and chase/flee differently.
+type SpeciesTuning struct {
+	cohesionFactor   float64
+	alignmentFactor  float64
+	separationFactor float64
+	avoidFactor      float64
+	fleeChaseFactor  float64
+}
+
+type SteeringSystem struct {
+	neighborhoodRange float64
+	avoidRange        float64
+	maxForce          float64
+	maxSpeed          float64
+
+	// tuning is indexed by Species so each species' flocking/avoidance/
+	// chase-flee weights can be set independently.
+	tuning [2]SpeciesTuning
+
+	// workerCount controls how many goroutines shard the boid range across;
+	// sequential forces a single-goroutine pass regardless of workerCount so
+	// the two modes can be benchmarked against each other.
+	workerCount int
+	sequential  bool
+}
+
+func (bs *SteeringSystem) Update() {
+	if bs.sequential || bs.workerCount <= 1 {
+		bs.updateRange(0, BoidsCount)
+		return
+	}
+
+	chunk := (BoidsCount + bs.workerCount - 1) / bs.workerCount
+
+	var wg sync.WaitGroup
+	for start := 0; start < BoidsCount; start += chunk {
+		end := start + chunk
+		if end > BoidsCount {
+			end = BoidsCount
+		}
+
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			bs.updateRange(start, end)
+		}(start, end)
+	}
+	wg.Wait()
+}
+
+// updateRange computes acceleration for boids in [start, end) using its own
+// scratch vectors, so concurrent shards never share mutable state.
+func (bs *SteeringSystem) updateRange(start, end int) {
+	var alignmentSteering, cohesionSteering, separationSteering Vector2D
+	var avoidSteering, speciesSteering Vector2D
+
+	positions := &positionComponents[readBuffer()]
+	velocities := &velocityComponents[readBuffer()]
+
+	for i := start; i < end; i++ {
+		tuning := bs.tuning[speciesComponents[i]]
+
+		alignmentSteering.Multiply(0)
+		cohesionSteering.Multiply(0)
+		separationSteering.Multiply(0)
+		avoidSteering.Multiply(0)
+		speciesSteering.Multiply(0)
+
+		var neighborCount float64 = 0.0
+		var speciesNeighborCount float64 = 0.0
+
+		rangeRec := NewRectangle(
+			positions[i],
+			bs.neighborhoodRange,
+			bs.neighborhoodRange,
+		)
+
+		neighbours := neighborIndex.Query(rangeRec, nil)
+
+		for _, entity := range neighbours {
+			if entity.Kind != EntityBoid || entity.Index == i {
+				continue
+			}
+
+			j := entity.Index
+			otherPosition := &positions[j]
+			otherVelocity := &velocities[j]
+
+			d := positions[i].Distance(otherPosition)
+			alignmentSteering.Add(otherVelocity)
+			cohesionSteering.Add(otherPosition)
+
+			diff := positions[i]
+			diff.Subtract(otherPosition)
+			diff.Divide(d) // Not squared?
+			separationSteering.Add(&diff)
+
+			neighborCount++
+
+			if speciesComponents[j] != speciesComponents[i] {
+				away := positions[i]
+				away.Subtract(otherPosition)
+				away.Divide(d)
+				speciesSteering.Add(&away)
+				speciesNeighborCount++
+			}
+		}
+
+		if neighborCount > 0 {
+			alignmentSteering.Divide(neighborCount)
+			alignmentSteering.SetMagnitude(bs.maxSpeed)
+			alignmentSteering.Subtract(&velocities[i])
+			alignmentSteering.Limit(bs.maxForce)
+
+			cohesionSteering.Divide(neighborCount)
+			cohesionSteering.Subtract(&positions[i])
+			cohesionSteering.SetMagnitude(bs.maxSpeed)
+			cohesionSteering.Subtract(&velocities[i])
+			cohesionSteering.Limit(bs.maxForce)
+
+			separationSteering.Divide(neighborCount)
+			separationSteering.SetMagnitude(bs.maxSpeed)
+			separationSteering.Subtract(&velocities[i])
+			separationSteering.SetMagnitude(bs.maxForce)
+
+			alignmentSteering.Multiply(tuning.alignmentFactor)
+			cohesionSteering.Multiply(tuning.cohesionFactor)
+			separationSteering.Multiply(tuning.separationFactor)
+
+			accelerationComponents[i].Add(&alignmentSteering)
+			accelerationComponents[i].Add(&cohesionSteering)
+			accelerationComponents[i].Add(&separationSteering)
+			accelerationComponents[i].Divide(3) // WHY?
+		}
+
+		// Predators steer toward nearby prey, prey steers away from nearby
+		// predators, along the mean of the unit vectors between them.
+		if speciesNeighborCount > 0 {
+			speciesSteering.Divide(speciesNeighborCount)
+			speciesSteering.SetMagnitude(bs.maxSpeed)
+			speciesSteering.Subtract(&velocities[i])
+			speciesSteering.Limit(bs.maxForce)
+
+			if speciesComponents[i] == SpeciesPredator {
+				speciesSteering.Multiply(-tuning.fleeChaseFactor)
+			} else {
+				speciesSteering.Multiply(tuning.fleeChaseFactor)
+			}
+
+			accelerationComponents[i].Add(&speciesSteering)
+		}
+
+		// Obstacle avoidance: look ahead along the boid's current heading and
+		// push away from anything found there, stronger the closer it is.
+		if avoidCount := bs.queryObstacleAvoidance(i, positions, velocities, &avoidSteering); avoidCount > 0 {
+			avoidSteering.Divide(avoidCount)
+			avoidSteering.Multiply(tuning.avoidFactor)
+			avoidSteering.Limit(bs.maxForce)
+			accelerationComponents[i].Add(&avoidSteering)
+		}
+
+		neighborCountComponents[i] = int(neighborCount)
+	}
+}
+
+// queryObstacleAvoidance accumulates a repulsive force (proportional to
+// 1/distance) from every obstacle inside a look-ahead box in front of boid i,
+// and returns how many obstacles contributed to it.
+func (bs *SteeringSystem) queryObstacleAvoidance(i int, positions, velocities *[BoidsCount]Position, avoidSteering *Vector2D) float64 {
+	heading := velocities[i]
+	speed := math.Hypot(heading.X, heading.Y)
+	if speed > 0 {
+		heading.Divide(speed)
+	} else {
+		heading = Vector2D{X: 0, Y: -1}
+	}
+
+	lookAheadCenter := positions[i]
+	offset := heading
+	offset.Multiply(bs.avoidRange / 2)
+	lookAheadCenter.Add(&offset)
+
+	lookAheadRec := NewRectangle(lookAheadCenter, bs.avoidRange, bs.avoidRange)
+	neighbours := neighborIndex.Query(lookAheadRec, nil)
+
+	var avoidCount float64 = 0.0
+	for _, entity := range neighbours {
+		if entity.Kind != EntityObstacle {
+			continue
+		}
+
+		obstacle := &obstacleComponents[entity.Index]
+		d := obstacle.distance(&positions[i])
+
+		away := positions[i]
+		away.Subtract(&obstacle.Position)
+		if centerDistance := positions[i].Distance(&obstacle.Position); centerDistance > 0 {
+			away.Divide(centerDistance)
+		}
+		away.Divide(d)
+		avoidSteering.Add(&away)
+
+		avoidCount++
+	}
+
+	return avoidCount
+}